@@ -0,0 +1,54 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// capabilities reads the process' CapEff, CapPrm, CapInh, CapBnd, and CapAmb
+// masks from /proc/<pid>/status.
+func (pid Pid) capabilities() Capabilities {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return Capabilities{}
+	}
+	defer f.Close()
+
+	var caps Capabilities
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "CapInh":
+			caps.Inheritable = parseCapset(value)
+		case "CapPrm":
+			caps.Permitted = parseCapset(value)
+		case "CapEff":
+			caps.Effective = parseCapset(value)
+		case "CapBnd":
+			caps.Bounding = parseCapset(value)
+		case "CapAmb":
+			caps.Ambient = parseCapset(value)
+		}
+	}
+
+	return caps
+}
+
+// parseCapset parses a hex capability mask as reported in /proc/<pid>/status.
+func parseCapset(hex string) capset {
+	n, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return 0
+	}
+	return capset(n)
+}
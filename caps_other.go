@@ -0,0 +1,11 @@
+// Copyright © 2023 The Gomon Project.
+
+//go:build !linux
+
+package main
+
+// capabilities is a no-op on platforms other than Linux, so -caps degrades
+// gracefully: the capability columns are simply empty.
+func (pid Pid) capabilities() Capabilities {
+	return Capabilities{}
+}
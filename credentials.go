@@ -0,0 +1,17 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"os/user"
+	"strconv"
+)
+
+// username looks up the login name for a uid, returning "" if it cannot be resolved.
+func username(uid uint32) string {
+	u, err := user.LookupId(strconv.Itoa(int(uid)))
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zosmac/gocore"
 )
@@ -13,13 +14,30 @@ import (
 type (
 	// flagpids, because []Pid cannot be a receiver type for flag.Value Set and String.
 	flagpids []Pid
+
+	// flagenvs accumulates repeated "-env KEY=<regexp>" arguments.
+	flagenvs []string
+
+	// flagduration adapts time.Duration for flag.Value, since time.Duration has
+	// no Set(string) error method of its own.
+	flagduration time.Duration
 )
 
 var (
 	// flags defines the command line flags.
 	flags = struct {
-		verbose bool
-		pids    flagpids
+		verbose   bool
+		pids      flagpids
+		caps      bool
+		capsDiff  bool
+		ns        bool
+		groupByNs string
+		output    string
+		exe       string
+		arg       string
+		user      string
+		env       flagenvs
+		watch     flagduration
 	}{}
 )
 
@@ -40,6 +58,76 @@ func init() {
 		"[-pids <pid>[,<pid>...]]",
 		"Print process tree for specific processes selected with comma separated list `pid[,pid...]`",
 	)
+
+	gocore.Flags.Var(
+		&flags.caps,
+		"caps",
+		"[-caps]",
+		"Annotate each process with its effective, permitted, inheritable, bounding, and ambient capability sets",
+	)
+
+	gocore.Flags.Var(
+		&flags.capsDiff,
+		"caps-diff",
+		"[-caps-diff]",
+		"With -caps, highlight capability bits a process gained or dropped relative to its parent",
+	)
+
+	gocore.Flags.Var(
+		&flags.ns,
+		"ns",
+		"[-ns]",
+		"Include the pid, mnt, net, user, uts, ipc, cgroup, and time namespace inodes for each process",
+	)
+
+	gocore.Flags.Var(
+		&flags.groupByNs,
+		"group-by-ns",
+		"[-group-by-ns <pid|mnt|net|user|uts|ipc|cgroup|time>]",
+		"Nest processes under a synthetic node for each distinct namespace `kind` they share, to reveal container boundaries",
+	)
+
+	gocore.Flags.Var(
+		&flags.output,
+		"output",
+		"[-output <json|yaml|dot|mermaid>]",
+		"Render the process tree in the given `format` instead of the default indented text",
+	)
+
+	gocore.Flags.Var(
+		&flags.exe,
+		"exe",
+		"[-exe <regexp>]",
+		"Print process tree for processes whose executable matches `regexp`",
+	)
+
+	gocore.Flags.Var(
+		&flags.arg,
+		"arg",
+		"[-arg <regexp>]",
+		"Print process tree for processes with a command line argument matching `regexp`",
+	)
+
+	gocore.Flags.Var(
+		&flags.user,
+		"user",
+		"[-user <name|uid>]",
+		"Print process tree for processes running as `name|uid`",
+	)
+
+	gocore.Flags.Var(
+		&flags.env,
+		"env",
+		"[-env <KEY>=<regexp>]",
+		"Print process tree for processes with environment variable `KEY` matching regexp; repeatable",
+	)
+
+	gocore.Flags.Var(
+		&flags.watch,
+		"watch",
+		"[-watch <duration>]",
+		"Refresh the process tree every `duration` (e.g. 2s), highlighting new, exited, and reparented processes",
+	)
 }
 
 // Set is a flag.Value interface method to enable logLevel as a command line flag.
@@ -64,3 +152,30 @@ func (pids flagpids) String() string {
 	}
 	return strings.Join(args, ",")
 }
+
+// Set is a flag.Value interface method, called once per occurrence of -env on
+// the command line, to accumulate each "KEY=<regexp>" pair.
+func (envs *flagenvs) Set(arg string) error {
+	*envs = append(*envs, arg)
+	return nil
+}
+
+// String is a flag.Value interface method to enable -env as a command line flag.
+func (envs flagenvs) String() string {
+	return strings.Join(envs, ",")
+}
+
+// Set is a flag.Value interface method to enable -watch as a command line flag.
+func (d *flagduration) Set(arg string) error {
+	v, err := time.ParseDuration(arg)
+	if err != nil {
+		return err
+	}
+	*d = flagduration(v)
+	return nil
+}
+
+// String is a flag.Value interface method to enable -watch as a command line flag.
+func (d flagduration) String() string {
+	return time.Duration(d).String()
+}
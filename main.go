@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zosmac/gocore"
 )
@@ -22,6 +23,11 @@ type (
 		Pid
 		Ppid Pid
 		CommandLine
+		Caps     Capabilities
+		Ns       Namespaces
+		Uid      uint32 `json:"uid" gomon:"property"`
+		Gid      uint32 `json:"gid" gomon:"property"`
+		Username string `json:"username,omitempty" gomon:"property"`
 	}
 
 	// CommandLine contains a process' command line arguments.
@@ -50,35 +56,71 @@ func main() {
 
 // Main builds and displays the process tree.
 func Main(ctx context.Context) error {
+	if d := time.Duration(flags.watch); d > 0 {
+		if flags.output != "" {
+			return fmt.Errorf("-watch does not support -output; watch only renders as text")
+		}
+		return watch(ctx, d)
+	}
+
 	tb := buildTable()
 	tr := buildTree(tb)
 
+	tb, tr, err := filterTree(tb, tr)
+	if err != nil {
+		return err
+	}
+
+	r := newRenderer(tb)
+	r.Begin()
+	// ancestors tracks the path from the root to the node at each depth as the
+	// traversal descends, so edges reflect the tree tr actually built (which, with
+	// -group-by-ns, nests processes under a synthetic group node) rather than each
+	// process' real OS parent.
+	var ancestors []Pid
+	for depth, pid := range tr.SortedFunc(execOrder(tb)) {
+		p := tb[pid]
+		r.Node(depth, p)
+		ancestors = ancestors[:depth]
+		if depth > 0 {
+			r.Edge(ancestors[depth-1], pid)
+		}
+		ancestors = append(ancestors, pid)
+	}
+	r.End()
+
+	return nil
+}
+
+// filterTree narrows tb/tr to the family (ancestors and descendants) of the
+// pids selected via -pids, -exe, -arg, -user, and -env, for reuse by both the
+// one-shot path and -watch's per-tick refresh. With no selection flags set,
+// it returns tb and tr unchanged.
+func filterTree(tb table, tr tree) (table, tree, error) {
 	var pids []Pid
 	for _, pid := range flags.pids {
 		if _, ok := tb[pid]; ok {
 			pids = append(pids, pid)
 		}
 	}
-	if len(pids) > 0 {
-		pt := table{}
-		for _, pid := range pids {
-			for _, pid := range tr.Family(pid).All() {
-				pt[pid] = tb[pid]
-			}
-		}
-		tr = buildTree(pt)
+
+	selected, err := selectPids(tb)
+	if err != nil {
+		return nil, nil, err
 	}
+	pids = append(pids, selected...)
 
-	for depth, pid := range tr.SortedFunc(func(a, b Pid) int {
-		return cmp.Or(
-			cmp.Compare(filepath.Base(tb[a].Executable), filepath.Base(tb[b].Executable)),
-			cmp.Compare(a, b),
-		)
-	}) {
-		display(depth, pid, tb[pid])
+	if len(pids) == 0 {
+		return tb, tr, nil
 	}
 
-	return nil
+	pt := table{}
+	for _, pid := range pids {
+		for _, pid := range tr.Family(pid).All() {
+			pt[pid] = tb[pid]
+		}
+	}
+	return pt, buildTree(pt), nil
 }
 
 // buildTable builds a process table and captures current process state.
@@ -91,6 +133,12 @@ func buildTable() table {
 	tb := make(map[Pid]*process, len(pids))
 	for _, pid := range pids {
 		if p := pid.process(); p != nil {
+			if flags.caps {
+				p.Caps = pid.capabilities()
+			}
+			if flags.ns || flags.groupByNs != "" {
+				p.Ns = pid.namespaces()
+			}
 			tb[pid] = p
 		}
 	}
@@ -98,21 +146,56 @@ func buildTable() table {
 	return tb
 }
 
-// buildTree builds the process tree.
+// buildTree builds the process tree. With -group-by-ns, every process sharing
+// a namespace is added as a direct leaf of a single synthetic node for that
+// namespace, instead of nesting under its real parent: a tree node can only
+// have one parent, so the group node must have one stable attachment point
+// (the root) rather than being spliced into each member's own ancestor chain,
+// which would just have the last member processed win the node.
 func buildTree(tb table) tree {
 	tr := tree{}
+	kind := flags.groupByNs
+	nsNodes := map[Pid]*process{} // collected separately; tb is still being ranged over below
+
 	for pid := range tb {
+		if kind != "" {
+			if inode, ok := namespaceInode(tb[pid].Ns, kind); ok {
+				nsPid := nsGroupPid(inode)
+				if _, ok := nsNodes[nsPid]; !ok {
+					nsNodes[nsPid] = nsGroupNode(nsPid, kind, inode)
+				}
+				tr.Add(nsPid, pid)
+				continue
+			}
+		}
+
 		var pids []Pid
-		for ; pid > 0; pid = tb[pid].Ppid {
-			pids = append([]Pid{pid}, pids...)
+		for p := pid; p > 0 && tb[p] != nil; p = tb[p].Ppid {
+			pids = append([]Pid{p}, pids...)
 		}
 		tr.Add(pids...)
 	}
+
+	for nsPid, p := range nsNodes {
+		tb[nsPid] = p
+	}
+
 	return tr
 }
 
+// execOrder orders pids by their executable's base name, then by pid, for a
+// stable display order.
+func execOrder(tb table) func(a, b Pid) int {
+	return func(a, b Pid) int {
+		return cmp.Or(
+			cmp.Compare(filepath.Base(tb[a].Executable), filepath.Base(tb[b].Executable)),
+			cmp.Compare(a, b),
+		)
+	}
+}
+
 // display shows the pid, command, arguments, and environment variables for a process.
-func display(indent int, _ Pid, p *process) {
+func display(indent int, p *process, parent *process) {
 	tab := strings.Repeat("|\t", indent)
 	var s string
 	if flags.verbose {
@@ -144,5 +227,76 @@ func display(indent int, _ Pid, p *process) {
 	} else {
 		cmd = filepath.Base(cmd)
 	}
-	fmt.Printf("%s\033[m %s%s%s\033[m\n", s, cmd, args, envs)
+	fmt.Printf("%s\033[m %s%s%s%s%s\033[m\n", s, capsDisplay(p, parent), nsDisplay(p), cmd, args, envs)
+}
+
+// nsDisplay renders the -ns annotation: the namespace inodes a process belongs to.
+func nsDisplay(p *process) string {
+	if !flags.ns {
+		return ""
+	}
+	return fmt.Sprintf(
+		"\033[36m[pid=%d mnt=%d net=%d user=%d uts=%d ipc=%d cgroup=%d time=%d]\033[m ",
+		p.Ns.Pid, p.Ns.Mnt, p.Ns.Net, p.Ns.User, p.Ns.Uts, p.Ns.Ipc, p.Ns.Cgroup, p.Ns.Time,
+	)
+}
+
+// capsDisplay renders the -caps annotation for a process: its non-empty capability
+// sets and, with -caps-diff, the bits it gained or dropped relative to its parent.
+func capsDisplay(p *process, parent *process) string {
+	if !flags.caps {
+		return ""
+	}
+
+	sets := []struct {
+		name string
+		c    capset
+	}{
+		{"eff", p.Caps.Effective},
+		{"prm", p.Caps.Permitted},
+		{"inh", p.Caps.Inheritable},
+		{"bnd", p.Caps.Bounding},
+		{"amb", p.Caps.Ambient},
+	}
+
+	var parts []string
+	for _, set := range sets {
+		if set.c != 0 {
+			parts = append(parts, fmt.Sprintf("%s=%s", set.name, set.c))
+		}
+	}
+
+	diff := ""
+	if flags.capsDiff && parent != nil {
+		diff = capsDiff(p.Caps, parent.Caps)
+	}
+
+	if len(parts) == 0 && diff == "" {
+		return ""
+	}
+	return fmt.Sprintf("\033[36m[%s]%s\033[m ", strings.Join(parts, " "), diff)
+}
+
+// capsDiff highlights capability bits a process gained (red) or dropped (dim)
+// relative to its parent, to surface privilege escalation paths in the tree.
+func capsDiff(child, parent Capabilities) string {
+	gained := (child.Effective &^ parent.Effective) |
+		(child.Permitted &^ parent.Permitted) |
+		(child.Inheritable &^ parent.Inheritable) |
+		(child.Bounding &^ parent.Bounding) |
+		(child.Ambient &^ parent.Ambient)
+	dropped := (parent.Effective &^ child.Effective) |
+		(parent.Permitted &^ child.Permitted) |
+		(parent.Inheritable &^ child.Inheritable) |
+		(parent.Bounding &^ child.Bounding) |
+		(parent.Ambient &^ child.Ambient)
+
+	var s string
+	if gained != 0 {
+		s += fmt.Sprintf(" \033[91m+%s\033[36m", gained)
+	}
+	if dropped != 0 {
+		s += fmt.Sprintf(" \033[2m-%s\033[22;36m", dropped)
+	}
+	return s
 }
@@ -54,6 +54,9 @@ func (pid Pid) process() *process {
 		Pid:         pid,
 		Ppid:        Pid(bsi.pbsi_ppid),
 		CommandLine: pid.commandLine(),
+		Uid:         uint32(bsi.pbsi_uid),
+		Gid:         uint32(bsi.pbsi_gid),
+		Username:    username(uint32(bsi.pbsi_uid)),
 	}
 }
 
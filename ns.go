@@ -0,0 +1,62 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import "fmt"
+
+type (
+	// Namespaces holds the Linux namespace inodes a process belongs to.
+	Namespaces struct {
+		Pid    uint64 `json:"pid,omitempty" gomon:"property"`
+		Mnt    uint64 `json:"mnt,omitempty" gomon:"property"`
+		Net    uint64 `json:"net,omitempty" gomon:"property"`
+		User   uint64 `json:"user,omitempty" gomon:"property"`
+		Uts    uint64 `json:"uts,omitempty" gomon:"property"`
+		Ipc    uint64 `json:"ipc,omitempty" gomon:"property"`
+		Cgroup uint64 `json:"cgroup,omitempty" gomon:"property"`
+		Time   uint64 `json:"time,omitempty" gomon:"property"`
+	}
+)
+
+// namespaceInode returns the inode for the named namespace kind (pid, mnt, net,
+// user, uts, ipc, cgroup, time) and whether that namespace was populated.
+func namespaceInode(ns Namespaces, kind string) (uint64, bool) {
+	switch kind {
+	case "pid":
+		return ns.Pid, ns.Pid != 0
+	case "mnt":
+		return ns.Mnt, ns.Mnt != 0
+	case "net":
+		return ns.Net, ns.Net != 0
+	case "user":
+		return ns.User, ns.User != 0
+	case "uts":
+		return ns.Uts, ns.Uts != 0
+	case "ipc":
+		return ns.Ipc, ns.Ipc != 0
+	case "cgroup":
+		return ns.Cgroup, ns.Cgroup != 0
+	case "time":
+		return ns.Time, ns.Time != 0
+	default:
+		return 0, false
+	}
+}
+
+// nsGroupPid derives the synthetic pid used to represent a namespace's group
+// node in the tree: the negation of its inode, which keeps it out of the range
+// of real, positive pids.
+func nsGroupPid(inode uint64) Pid {
+	return Pid(-int64(inode))
+}
+
+// nsGroupNode builds the synthetic process that labels a namespace's group node.
+func nsGroupNode(pid Pid, kind string, inode uint64) *process {
+	return &process{
+		Pid: pid,
+		CommandLine: CommandLine{
+			Executable: fmt.Sprintf("[%s:%d]", kind, inode),
+			Args:       []string{fmt.Sprintf("[%s:%d]", kind, inode)},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// namespaces reads the process' namespace inodes from /proc/<pid>/ns.
+func (pid Pid) namespaces() Namespaces {
+	return Namespaces{
+		Pid:    pid.nsInode("pid"),
+		Mnt:    pid.nsInode("mnt"),
+		Net:    pid.nsInode("net"),
+		User:   pid.nsInode("user"),
+		Uts:    pid.nsInode("uts"),
+		Ipc:    pid.nsInode("ipc"),
+		Cgroup: pid.nsInode("cgroup"),
+		Time:   pid.nsInode("time"),
+	}
+}
+
+// nsInode reads the inode number from a single /proc/<pid>/ns/<kind> symlink,
+// which has the form "<kind>:[<inode>]".
+func (pid Pid) nsInode(kind string) uint64 {
+	link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, kind))
+	if err != nil {
+		return 0
+	}
+
+	_, inode, ok := strings.Cut(link, "[")
+	if !ok {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,11 @@
+// Copyright © 2023 The Gomon Project.
+
+//go:build !linux
+
+package main
+
+// namespaces is a no-op on platforms other than Linux, so -ns and -group-by-ns
+// degrade gracefully: processes simply report no namespace membership.
+func (pid Pid) namespaces() Namespaces {
+	return Namespaces{}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// renderer abstracts how a traversal of the process tree is turned into output,
+	// so the same traversal in Main can drive text, JSON, YAML, Graphviz, or Mermaid.
+	renderer interface {
+		Begin()
+		Node(depth int, p *process)
+		Edge(parent, child Pid)
+		End()
+	}
+
+	// textRenderer reproduces the original ANSI-decorated indented listing.
+	textRenderer struct {
+		tb table
+	}
+
+	// docNode is the nested document shape emitted by the json and yaml renderers.
+	docNode struct {
+		Pid        Pid        `json:"pid" yaml:"pid"`
+		Ppid       Pid        `json:"ppid" yaml:"ppid"`
+		Executable string     `json:"executable" yaml:"executable"`
+		Args       []string   `json:"args,omitempty" yaml:"args,omitempty"`
+		Envs       []string   `json:"envs,omitempty" yaml:"envs,omitempty"`
+		Children   []*docNode `json:"children,omitempty" yaml:"children,omitempty"`
+	}
+
+	// docRenderer builds a nested document from the flat Node/Edge traversal and
+	// marshals it as JSON or YAML on End.
+	docRenderer struct {
+		yaml    bool
+		nodes   map[Pid]*docNode
+		order   []Pid
+		isChild map[Pid]bool
+	}
+
+	// dotRenderer emits a Graphviz digraph, one edge per parent/child relation.
+	dotRenderer struct{}
+
+	// mermaidRenderer emits a Mermaid flowchart for embedding in markdown.
+	mermaidRenderer struct{}
+)
+
+// newRenderer selects the renderer named by the -output flag.
+func newRenderer(tb table) renderer {
+	switch flags.output {
+	case "json":
+		return &docRenderer{nodes: map[Pid]*docNode{}, isChild: map[Pid]bool{}}
+	case "yaml":
+		return &docRenderer{yaml: true, nodes: map[Pid]*docNode{}, isChild: map[Pid]bool{}}
+	case "dot":
+		return &dotRenderer{}
+	case "mermaid":
+		return &mermaidRenderer{}
+	default:
+		return &textRenderer{tb: tb}
+	}
+}
+
+func (textRenderer) Begin() {}
+
+func (r *textRenderer) Node(depth int, p *process) {
+	display(depth, p, r.tb[p.Ppid])
+}
+
+func (textRenderer) Edge(Pid, Pid) {}
+
+func (textRenderer) End() {}
+
+func (r *docRenderer) Begin() {}
+
+func (r *docRenderer) Node(_ int, p *process) {
+	r.nodes[p.Pid] = &docNode{
+		Pid:        p.Pid,
+		Ppid:       p.Ppid,
+		Executable: p.Executable,
+		Args:       p.Args,
+		Envs:       p.Envs,
+	}
+	r.order = append(r.order, p.Pid)
+}
+
+func (r *docRenderer) Edge(parent, child Pid) {
+	pn, cn := r.nodes[parent], r.nodes[child]
+	if pn == nil || cn == nil {
+		return
+	}
+	pn.Children = append(pn.Children, cn)
+	r.isChild[child] = true
+}
+
+func (r *docRenderer) End() {
+	var roots []*docNode
+	for _, pid := range r.order {
+		if !r.isChild[pid] {
+			roots = append(roots, r.nodes[pid])
+		}
+	}
+
+	var buf []byte
+	var err error
+	if r.yaml {
+		buf, err = yaml.Marshal(roots)
+	} else {
+		buf, err = json.MarshalIndent(roots, "", "  ")
+	}
+	if err != nil {
+		panic(fmt.Errorf("could not marshal process tree %v", err))
+	}
+	fmt.Println(string(buf))
+}
+
+func (dotRenderer) Begin() {
+	fmt.Println("digraph gotree {")
+}
+
+func (dotRenderer) Node(_ int, p *process) {
+	fmt.Printf("\t%d [label=%q];\n", p.Pid, label(p))
+}
+
+func (dotRenderer) Edge(parent, child Pid) {
+	fmt.Printf("\t%d -> %d;\n", parent, child)
+}
+
+func (dotRenderer) End() {
+	fmt.Println("}")
+}
+
+func (mermaidRenderer) Begin() {
+	fmt.Println("flowchart TD")
+}
+
+func (mermaidRenderer) Node(_ int, p *process) {
+	fmt.Printf("\t%d[%q]\n", p.Pid, label(p))
+}
+
+func (mermaidRenderer) Edge(parent, child Pid) {
+	fmt.Printf("\t%d --> %d\n", parent, child)
+}
+
+func (mermaidRenderer) End() {}
+
+// label renders the short "pid command" label shared by the dot and mermaid renderers.
+func label(p *process) string {
+	var cmd string
+	if len(p.Args) > 0 {
+		cmd = p.Args[0]
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", p.Pid, cmd))
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// envMatch pairs an environment variable name with the regexp its value must match.
+type envMatch struct {
+	key string
+	re  *regexp.Regexp
+}
+
+// selectPids returns the pids in tb matching every predicate supplied via -exe,
+// -arg, -user, and -env, generalizing -pids into content based selection, the
+// same pattern pgrep/procstat use to target processes by name rather than pid.
+func selectPids(tb table) ([]Pid, error) {
+	if flags.user != "" && !userFilterSupported {
+		return nil, fmt.Errorf("-user is not supported on this platform")
+	}
+
+	var exeRe, argRe *regexp.Regexp
+	if flags.exe != "" {
+		var err error
+		if exeRe, err = regexp.Compile(flags.exe); err != nil {
+			return nil, fmt.Errorf("-exe: %w", err)
+		}
+	}
+	if flags.arg != "" {
+		var err error
+		if argRe, err = regexp.Compile(flags.arg); err != nil {
+			return nil, fmt.Errorf("-arg: %w", err)
+		}
+	}
+
+	var envRes []envMatch
+	for _, kv := range flags.env {
+		key, pattern, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("-env %s: %w", key, err)
+		}
+		envRes = append(envRes, envMatch{key: key, re: re})
+	}
+
+	uid, hasUser := lookupUid(flags.user)
+	if flags.user != "" && !hasUser {
+		return nil, fmt.Errorf("-user: unknown user %q", flags.user)
+	}
+
+	if exeRe == nil && argRe == nil && !hasUser && len(envRes) == 0 {
+		return nil, nil
+	}
+
+	var pids []Pid
+	for pid, p := range tb {
+		if exeRe != nil && !exeRe.MatchString(p.Executable) {
+			continue
+		}
+		if argRe != nil && !matchAny(argRe, p.Args) {
+			continue
+		}
+		if hasUser && p.Uid != uid {
+			continue
+		}
+		if !matchEnvs(envRes, p.Envs) {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids, nil
+}
+
+// lookupUid resolves -user's `name|uid` argument to a uid.
+func lookupUid(arg string) (uid uint32, ok bool) {
+	if arg == "" {
+		return 0, false
+	}
+	if n, err := strconv.ParseUint(arg, 10, 32); err == nil {
+		return uint32(n), true
+	}
+	u, err := user.Lookup(arg)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(n), true
+}
+
+// matchAny reports whether re matches any of ss.
+func matchAny(re *regexp.Regexp, ss []string) bool {
+	for _, s := range ss {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEnvs reports whether envs satisfies every KEY=<regexp> predicate in envRes.
+func matchEnvs(envRes []envMatch, envs []string) bool {
+	for _, em := range envRes {
+		found := false
+		for _, e := range envs {
+			key, value, ok := strings.Cut(e, "=")
+			if ok && key == em.key && em.re.MatchString(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zosmac/gocore"
+)
+
+// getPids gets the list of active processes by pid.
+func getPids() ([]Pid, error) {
+	des, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, gocore.Error("ReadDir", err)
+	}
+
+	var pids []Pid
+	for _, de := range des {
+		pid, err := strconv.Atoi(de.Name())
+		if err != nil {
+			continue // not a pid directory
+		}
+		pids = append(pids, Pid(pid))
+	}
+
+	return pids, nil
+}
+
+func (pid Pid) process() *process {
+	buf, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil
+	}
+
+	// the command name is enclosed in parentheses and may itself contain spaces
+	// or parentheses, so locate it between the first '(' and the last ')'
+	// before parsing the remaining whitespace separated fields.
+	open := bytes.IndexByte(buf, '(')
+	close := bytes.LastIndexByte(buf, ')')
+	if open < 0 || close < open {
+		return nil
+	}
+
+	fields := strings.Fields(string(buf[close+1:]))
+	if len(fields) < 2 {
+		return nil
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil
+	}
+
+	uid, gid := pid.credentials()
+
+	return &process{
+		Pid:         pid,
+		Ppid:        Pid(ppid),
+		CommandLine: pid.commandLine(),
+		Uid:         uid,
+		Gid:         gid,
+		Username:    username(uid),
+	}
+}
+
+// credentials reads the process' effective uid and gid from /proc/<pid>/status.
+func (pid Pid) credentials() (uid, gid uint32) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) < 2 {
+			continue
+		}
+		switch key {
+		case "Uid":
+			if n, err := strconv.ParseUint(fields[1], 10, 32); err == nil {
+				uid = uint32(n)
+			}
+		case "Gid":
+			if n, err := strconv.ParseUint(fields[1], 10, 32); err == nil {
+				gid = uint32(n)
+			}
+		}
+	}
+
+	return uid, gid
+}
+
+// commandLine retrieves process command, arguments, and environment.
+func (pid Pid) commandLine() CommandLine {
+	executable, _ := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+
+	args := nulSeparated(fmt.Sprintf("/proc/%d/cmdline", pid))
+	envs := nulSeparated(fmt.Sprintf("/proc/%d/environ", pid))
+
+	if executable == "" && len(args) > 0 {
+		executable = args[0]
+	}
+
+	return CommandLine{
+		Executable: executable,
+		Args:       args,
+		Envs:       envs,
+	}
+}
+
+// nulSeparated reads a proc file of NUL separated strings, such as cmdline or environ.
+func nulSeparated(path string) []string {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	ss := bytes.FieldsFunc(buf, func(r rune) bool { return r == 0 })
+	ret := make([]string, len(ss))
+	for i, s := range ss {
+		ret[i] = string(s)
+	}
+	return ret
+}
@@ -0,0 +1,244 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/zosmac/gocore"
+)
+
+const maxPath = 260 // windows.MAX_PATH
+
+const processVMRead = 0x0010 // windows.PROCESS_VM_READ, which syscall does not define
+
+var (
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+	modntdll    = syscall.NewLazyDLL("ntdll.dll")
+
+	procQueryFullProcessImageName = modkernel32.NewProc("QueryFullProcessImageNameW")
+	procNtQueryInformationProcess = modntdll.NewProc("NtQueryInformationProcess")
+)
+
+// getPids gets the list of active processes by pid.
+func getPids() ([]Pid, error) {
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, gocore.Error("CreateToolhelp32Snapshot", err)
+	}
+	defer syscall.CloseHandle(snap)
+
+	var pe syscall.ProcessEntry32
+	pe.Size = uint32(unsafe.Sizeof(pe))
+
+	var pids []Pid
+	for err = syscall.Process32First(snap, &pe); err == nil; err = syscall.Process32Next(snap, &pe) {
+		pids = append(pids, Pid(pe.ProcessID))
+	}
+
+	return pids, nil
+}
+
+func (pid Pid) process() *process {
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, uint32(pid))
+	if err != nil {
+		return nil
+	}
+	defer syscall.CloseHandle(snap)
+
+	var pe syscall.ProcessEntry32
+	pe.Size = uint32(unsafe.Sizeof(pe))
+
+	for err = syscall.Process32First(snap, &pe); err == nil; err = syscall.Process32Next(snap, &pe) {
+		if Pid(pe.ProcessID) != pid {
+			continue
+		}
+		return &process{
+			Pid:         pid,
+			Ppid:        Pid(pe.ParentProcessID),
+			CommandLine: pid.commandLine(),
+		}
+	}
+
+	return nil
+}
+
+// commandLine retrieves process command, arguments, and environment by opening the
+// process and reading its command line and environment block from the PEB.
+func (pid Pid) commandLine() CommandLine {
+	h, err := syscall.OpenProcess(
+		syscall.PROCESS_QUERY_INFORMATION|processVMRead,
+		false,
+		uint32(pid),
+	)
+	if err != nil {
+		return CommandLine{}
+	}
+	defer syscall.CloseHandle(h)
+
+	executable, _ := queryFullProcessImageName(h)
+
+	args, envs := processParameters(h)
+	if executable == "" && len(args) > 0 {
+		executable = args[0]
+	}
+
+	return CommandLine{
+		Executable: executable,
+		Args:       args,
+		Envs:       envs,
+	}
+}
+
+// queryFullProcessImageName retrieves the full path of the process' executable.
+func queryFullProcessImageName(h syscall.Handle) (string, error) {
+	buf := make([]uint16, maxPath)
+	size := uint32(len(buf))
+	rv, _, err := procQueryFullProcessImageName.Call(
+		uintptr(h),
+		0,
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if rv == 0 {
+		return "", err
+	}
+	return syscall.UTF16ToString(buf[:size]), nil
+}
+
+// processBasicInformation mirrors the PROCESS_BASIC_INFORMATION structure returned
+// by NtQueryInformationProcess, just enough of it to locate the PEB.
+type processBasicInformation struct {
+	reserved1       uintptr
+	pebBaseAddress  uintptr
+	reserved2       [2]uintptr
+	uniqueProcessID uintptr
+	reserved3       uintptr
+}
+
+// processParameters walks the process environment block (PEB) of an opened process
+// to read its command line arguments and environment variables.
+func processParameters(h syscall.Handle) (args, envs []string) {
+	var pbi processBasicInformation
+	rv, _, _ := procNtQueryInformationProcess.Call(
+		uintptr(h),
+		0, // ProcessBasicInformation
+		uintptr(unsafe.Pointer(&pbi)),
+		unsafe.Sizeof(pbi),
+		0,
+	)
+	if rv != 0 || pbi.pebBaseAddress == 0 {
+		return nil, nil
+	}
+
+	// offsets of ProcessParameters within the PEB, and of CommandLine/Environment
+	// within RTL_USER_PROCESS_PARAMETERS, for the 64-bit PEB layout.
+	const processParametersOffset = 0x20
+	const commandLineOffset = 0x70
+	const environmentOffset = 0x80
+
+	processParameters, err := readUintptr(h, pbi.pebBaseAddress+processParametersOffset)
+	if err != nil {
+		return nil, nil
+	}
+
+	if cmdline, err := readUnicodeString(h, processParameters+commandLineOffset); err == nil && cmdline != "" {
+		// the command line is a single space separated string, so split it with
+		// the same quoting rules the process itself uses to build its argv.
+		if p, err := syscall.UTF16PtrFromString(cmdline); err == nil {
+			var argc int32
+			if argv, err := syscall.CommandLineToArgv(p, &argc); err == nil {
+				for _, arg := range (*argv)[:argc] {
+					args = append(args, syscall.UTF16ToString((*arg)[:]))
+				}
+				syscall.LocalFree(syscall.Handle(uintptr(unsafe.Pointer(argv))))
+			}
+		}
+	}
+
+	if environment, err := readUintptr(h, processParameters+environmentOffset); err == nil {
+		if buf, err := readMemory(h, environment, 32*1024); err == nil {
+			envs = splitNul(utf16ToString(buf))
+		}
+	}
+
+	return args, envs
+}
+
+// readUintptr reads a pointer-sized value from the process' address space.
+func readUintptr(h syscall.Handle, addr uintptr) (uintptr, error) {
+	buf, err := readMemory(h, addr, unsafe.Sizeof(uintptr(0)))
+	if err != nil {
+		return 0, err
+	}
+	return uintptr(binary.LittleEndian.Uint64(buf)), nil
+}
+
+// readUnicodeString reads a UNICODE_STRING (length, maxlength, buffer pointer) and
+// the UTF-16 buffer it points to.
+func readUnicodeString(h syscall.Handle, addr uintptr) (string, error) {
+	header, err := readMemory(h, addr, 16)
+	if err != nil {
+		return "", err
+	}
+	length := binary.LittleEndian.Uint16(header[0:2])
+	buffer := uintptr(binary.LittleEndian.Uint64(header[8:16]))
+
+	buf, err := readMemory(h, buffer, uintptr(length))
+	if err != nil {
+		return "", err
+	}
+	return utf16ToString(buf), nil
+}
+
+// readMemory reads size bytes from the process' address space at addr.
+func readMemory(h syscall.Handle, addr uintptr, size uintptr) ([]byte, error) {
+	buf := make([]byte, size)
+	var n uintptr
+	if err := readProcessMemory(h, addr, &buf[0], size, &n); err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// readProcessMemory wraps the kernel32 ReadProcessMemory call.
+func readProcessMemory(h syscall.Handle, addr uintptr, buf *byte, size uintptr, n *uintptr) error {
+	proc := modkernel32.NewProc("ReadProcessMemory")
+	rv, _, err := proc.Call(
+		uintptr(h),
+		addr,
+		uintptr(unsafe.Pointer(buf)),
+		size,
+		uintptr(unsafe.Pointer(n)),
+	)
+	if rv == 0 {
+		return err
+	}
+	return nil
+}
+
+// utf16ToString converts a raw UTF-16LE byte buffer to a string.
+func utf16ToString(buf []byte) string {
+	u16 := make([]uint16, len(buf)/2)
+	for i := range u16 {
+		u16[i] = binary.LittleEndian.Uint16(buf[i*2 : i*2+2])
+	}
+	return string(utf16.Decode(u16))
+}
+
+// splitNul splits a NUL separated (and double NUL terminated) block of strings.
+func splitNul(s string) []string {
+	var ret []string
+	b := []byte(s)
+	if i := bytes.Index(b, []byte{0, 0}); i >= 0 {
+		b = b[:i] // drop the terminator and anything read beyond it
+	}
+	for _, s := range bytes.FieldsFunc(b, func(r rune) bool { return r == 0 }) {
+		ret = append(ret, string(s))
+	}
+	return ret
+}
@@ -0,0 +1,9 @@
+// Copyright © 2023 The Gomon Project.
+
+//go:build !windows
+
+package main
+
+// userFilterSupported reports whether Uid is populated meaningfully on this
+// platform, so -user can be compared against it.
+const userFilterSupported = true
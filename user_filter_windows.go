@@ -0,0 +1,9 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+// userFilterSupported is false on Windows: process() never populates Uid
+// there, so comparing against it would make -user match every process
+// (uid 0) or none at all, silently. selectPids rejects -user outright on
+// this platform instead.
+const userFilterSupported = false
@@ -0,0 +1,92 @@
+// Copyright © 2023 The Gomon Project.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// watch rebuilds and redisplays the process tree every interval until ctx is
+// done, highlighting pids that appeared, exited, or were reparented since the
+// previous iteration. This turns gotree from a one-shot reporter into a
+// top-style monitor without pulling in a TUI framework. Each tick is narrowed
+// by -pids/-exe/-arg/-user/-env through the same filterTree used by the
+// one-shot path, so those flags aren't silently ignored under -watch.
+func watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev table
+	for {
+		tb := buildTable()
+		tb, _, err := filterTree(tb, buildTree(tb))
+		if err != nil {
+			return err
+		}
+
+		displayWatch(tb, prev)
+		prev = tb
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// displayWatch clears the screen and renders tb, annotating pids that are new
+// (green), exited (red, strikethrough, shown once before being dropped), or
+// reparented (yellow, indicating daemonization) relative to prev.
+func displayWatch(tb, prev table) {
+	merged := table{}
+	state := map[Pid]byte{}
+
+	for pid, p := range tb {
+		merged[pid] = p
+		if prevP, ok := prev[pid]; !ok {
+			if prev != nil {
+				state[pid] = 'n'
+			}
+		} else if prevP.Ppid != p.Ppid {
+			state[pid] = 'r'
+		}
+	}
+	for pid, p := range prev {
+		if _, ok := tb[pid]; !ok {
+			merged[pid] = p
+			state[pid] = 'x'
+		}
+	}
+
+	tr := buildTree(merged)
+
+	fmt.Print("\033[H\033[2J")
+	for depth, pid := range tr.SortedFunc(execOrder(merged)) {
+		p := merged[pid]
+		watchLine(depth, p, merged[p.Ppid], state[pid])
+	}
+}
+
+// watchLine displays one process, wrapped in the ANSI color for its watch state.
+func watchLine(depth int, p, parent *process, state byte) {
+	var color string
+	switch state {
+	case 'n':
+		color = "\033[92m" // new: green
+	case 'r':
+		color = "\033[93m" // reparented: yellow
+	case 'x':
+		color = "\033[9;91m" // exited: strikethrough red
+	}
+
+	if color == "" {
+		display(depth, p, parent)
+		return
+	}
+	fmt.Print(color)
+	display(depth, p, parent)
+	fmt.Print("\033[m")
+}